@@ -0,0 +1,42 @@
+// Package backoff implements exponential backoff with jitter, used to
+// space out retries after a failing API call.
+package backoff
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Backoff tracks the retry state for a single operation. The zero value is
+// not usable; construct one with New.
+type Backoff struct {
+	min     time.Duration
+	max     time.Duration
+	attempt int
+}
+
+// New returns a Backoff whose base delay starts at min and doubles on each
+// subsequent call to Next, capped at max. Next jitters each base delay to
+// between half of it and all of it.
+func New(min, max time.Duration) *Backoff {
+	return &Backoff{min: min, max: max}
+}
+
+// Next returns the delay to wait before the next retry and advances the
+// backoff state. The delay is jittered to avoid retry storms when multiple
+// callers back off in lockstep.
+func (b *Backoff) Next() time.Duration {
+	delay := b.min << b.attempt
+	if delay <= 0 || delay > b.max {
+		delay = b.max
+	}
+	b.attempt++
+
+	jittered := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay/2 + jittered
+}
+
+// Reset clears the backoff state after a successful call.
+func (b *Backoff) Reset() {
+	b.attempt = 0
+}
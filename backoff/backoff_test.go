@@ -0,0 +1,31 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextStaysWithinBounds(t *testing.T) {
+	b := New(100*time.Millisecond, time.Second)
+
+	for i := 0; i < 10; i++ {
+		d := b.Next()
+		if d < 0 || d > time.Second {
+			t.Fatalf("Next() = %v, want between 0 and %v", d, time.Second)
+		}
+	}
+}
+
+func TestResetRestartsFromMin(t *testing.T) {
+	b := New(100*time.Millisecond, time.Second)
+
+	for i := 0; i < 5; i++ {
+		b.Next()
+	}
+	b.Reset()
+
+	d := b.Next()
+	if d > 100*time.Millisecond {
+		t.Fatalf("Next() after Reset() = %v, want <= %v", d, 100*time.Millisecond)
+	}
+}
@@ -0,0 +1,87 @@
+// Package config loads the JSON file that maps each managed domain to the
+// provider, credentials, and DNS records used to update it.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mikerybka/update-domains/mod"
+)
+
+// dynamicIP is the token a record's content can contain to mean "the
+// machine's current public IP", resolved at update time.
+const dynamicIP = "$IP"
+
+// RecordConfig is one desired DNS record, as declared in the config file.
+// Content may contain the literal token "$IP", which is substituted with
+// the detected public IP address when the record is applied.
+type RecordConfig struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	TTL     int    `json:"ttl"`
+	Prio    *int   `json:"prio,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+// Resolve turns rc into a mod.Record, substituting ip for any "$IP" token
+// in Content.
+func (rc RecordConfig) Resolve(ip string) mod.Record {
+	return mod.Record{
+		Name:    rc.Name,
+		Type:    rc.Type,
+		Content: strings.ReplaceAll(rc.Content, dynamicIP, ip),
+		TTL:     rc.TTL,
+		Prio:    rc.Prio,
+		Notes:   rc.Notes,
+	}
+}
+
+// defaultRecords is used when a domain declares no records of its own: the
+// original ddns behavior of pointing the root and wildcard at the current
+// IP. TTL is 600, Porkbun's minimum, so a reconcile run against a Porkbun
+// zone - which clamps anything lower - doesn't see its own clamped value
+// reflected back as a perpetual drift and re-issue the same update forever.
+var defaultRecords = []RecordConfig{
+	{Name: "@", Type: "A", Content: dynamicIP, TTL: 600},
+	{Name: "*", Type: "A", Content: dynamicIP, TTL: 600},
+}
+
+// DomainConfig describes how a single domain should be managed.
+type DomainConfig struct {
+	Provider    string            `json:"provider"`
+	Credentials map[string]string `json:"credentials"`
+	Records     []RecordConfig    `json:"records,omitempty"`
+}
+
+// DesiredRecords returns the records dc declares, or defaultRecords if it
+// declares none.
+func (dc DomainConfig) DesiredRecords() []RecordConfig {
+	if len(dc.Records) == 0 {
+		return defaultRecords
+	}
+	return dc.Records
+}
+
+// Config is the top-level shape of the config file, keyed by domain name.
+type Config struct {
+	Domains map[string]DomainConfig `json:"domains"`
+}
+
+// Load reads and parses the config file at path.
+func Load(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening config: %w", err)
+	}
+	defer f.Close()
+
+	var cfg Config
+	if err := json.NewDecoder(f).Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import "testing"
+
+func TestRecordConfigResolve(t *testing.T) {
+	tests := []struct {
+		name string
+		rc   RecordConfig
+		ip   string
+		want string
+	}{
+		{
+			name: "dynamic IP is substituted",
+			rc:   RecordConfig{Name: "@", Type: "A", Content: "$IP"},
+			ip:   "1.2.3.4",
+			want: "1.2.3.4",
+		},
+		{
+			name: "static content is left alone",
+			rc:   RecordConfig{Name: "@", Type: "MX", Content: "mail.example.com"},
+			ip:   "1.2.3.4",
+			want: "mail.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rc.Resolve(tt.ip).Content
+			if got != tt.want {
+				t.Errorf("Resolve().Content = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDomainConfigDesiredRecords(t *testing.T) {
+	t.Run("falls back to defaults when unset", func(t *testing.T) {
+		dc := DomainConfig{Provider: "porkbun"}
+		records := dc.DesiredRecords()
+		if len(records) != 2 {
+			t.Fatalf("DesiredRecords() = %v, want 2 default records", records)
+		}
+		for _, r := range records {
+			if r.TTL != 600 {
+				t.Errorf("DesiredRecords() TTL = %d, want 600 (Porkbun's minimum, to avoid a perpetual update)", r.TTL)
+			}
+		}
+	})
+
+	t.Run("uses declared records when set", func(t *testing.T) {
+		dc := DomainConfig{
+			Provider: "porkbun",
+			Records: []RecordConfig{
+				{Name: "@", Type: "AAAA", Content: "$IP"},
+			},
+		}
+		records := dc.DesiredRecords()
+		if len(records) != 1 || records[0].Type != "AAAA" {
+			t.Fatalf("DesiredRecords() = %v, want the single declared AAAA record", records)
+		}
+	})
+}
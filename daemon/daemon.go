@@ -0,0 +1,95 @@
+// Package daemon runs the update loop continuously, only touching DNS when
+// the machine's public IP actually changes.
+package daemon
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mikerybka/update-domains/backoff"
+	"github.com/mikerybka/update-domains/config"
+	"github.com/mikerybka/update-domains/ipresolver"
+	"github.com/mikerybka/update-domains/providers"
+	"github.com/mikerybka/update-domains/reconcile"
+	"github.com/mikerybka/update-domains/state"
+)
+
+// Config controls how the daemon polls for IP changes and applies them.
+type Config struct {
+	Resolver   ipresolver.Resolver
+	Domains    *config.Config
+	StatePath  string
+	Interval   time.Duration
+	Prune      bool
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	Reconcile  reconcile.Options
+}
+
+// Run polls Resolver every Interval and, whenever the resolved IP differs
+// from the last one successfully applied, reconciles every configured
+// domain against it. It blocks until ctx is canceled.
+func Run(ctx context.Context, cfg Config) error {
+	st, err := state.Load(cfg.StatePath)
+	if err != nil {
+		return err
+	}
+
+	bo := backoff.New(cfg.MinBackoff, cfg.MaxBackoff)
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := tick(ctx, cfg, st); err != nil {
+			log.Printf("daemon: %v", err)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(bo.Next()):
+			}
+			continue
+		}
+		bo.Reset()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func tick(ctx context.Context, cfg Config, st *state.State) error {
+	ip, err := cfg.Resolver.ResolveIP(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ip == st.LastIP {
+		return nil
+	}
+
+	log.Printf("daemon: public IP changed to %s, updating domains", ip)
+	for domain, domainCfg := range cfg.Domains.Domains {
+		if err := updateDomain(ctx, domain, ip, domainCfg, cfg.Prune, cfg.Reconcile); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Reconcile.DryRun {
+		return nil
+	}
+
+	st.LastIP = ip
+	st.LastUpdateTime = time.Now()
+	return state.Save(cfg.StatePath, st)
+}
+
+func updateDomain(ctx context.Context, domain, ip string, domainCfg config.DomainConfig, prune bool, opts reconcile.Options) error {
+	provider, err := providers.New(domainCfg.Provider, domainCfg.Credentials)
+	if err != nil {
+		return err
+	}
+	return reconcile.UpdateDomain(ctx, provider, domain, ip, domainCfg, prune, opts)
+}
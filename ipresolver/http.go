@@ -0,0 +1,49 @@
+package ipresolver
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// httpResolver fetches the caller's public IP from a plain-text endpoint
+// such as https://ifconfig.me or https://api.ipify.org.
+type httpResolver struct {
+	url    string
+	client *http.Client
+}
+
+func (r *httpResolver) ResolveIP(ctx context.Context) (string, error) {
+	client := r.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("resolving IP via %s: %s", r.url, resp.Status)
+	}
+
+	ip := strings.TrimSpace(string(body))
+	if net.ParseIP(ip) == nil {
+		return "", fmt.Errorf("resolving IP via %s: %q is not an IP address", r.url, ip)
+	}
+	return ip, nil
+}
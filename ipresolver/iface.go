@@ -0,0 +1,37 @@
+package ipresolver
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// ifaceResolver reports the first non-loopback IPv4 address bound to a named
+// local network interface, for machines with a publicly routable address
+// assigned directly (e.g. a VPS with no NAT in front of it).
+type ifaceResolver struct {
+	name string
+}
+
+func (r *ifaceResolver) ResolveIP(ctx context.Context) (string, error) {
+	iface, err := net.InterfaceByName(r.name)
+	if err != nil {
+		return "", err
+	}
+
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return "", err
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() {
+			continue
+		}
+		if ip := ipNet.IP.To4(); ip != nil {
+			return ip.String(), nil
+		}
+	}
+	return "", fmt.Errorf("no usable address bound to interface %q", r.name)
+}
@@ -0,0 +1,37 @@
+// Package ipresolver determines the machine's current public IP address,
+// for use by the daemon's change-detection loop.
+package ipresolver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Resolver reports the machine's current public IP address.
+type Resolver interface {
+	ResolveIP(ctx context.Context) (string, error)
+}
+
+// New builds a Resolver from a spec string of the form "kind:arg":
+//
+//	http:https://ifconfig.me     - GET the URL, the body is the IP
+//	stun:stun.l.google.com:19302 - query a STUN server for the reflexive address
+//	iface:eth0                   - use the first non-loopback IPv4 address bound to a local interface
+func New(spec string) (Resolver, error) {
+	kind, arg, ok := strings.Cut(spec, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid resolver spec %q, want kind:arg", spec)
+	}
+
+	switch kind {
+	case "http":
+		return &httpResolver{url: arg}, nil
+	case "stun":
+		return &stunResolver{server: arg}, nil
+	case "iface":
+		return &ifaceResolver{name: arg}, nil
+	default:
+		return nil, fmt.Errorf("unknown resolver kind %q", kind)
+	}
+}
@@ -0,0 +1,121 @@
+package ipresolver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// stunResolver determines the caller's public IP by sending a STUN (RFC
+// 5389) binding request and reading the reflexive address back out of the
+// XOR-MAPPED-ADDRESS attribute. It's a minimal client: no retransmission,
+// no TCP fallback, just enough to work against a well-behaved public STUN
+// server.
+type stunResolver struct {
+	server string
+}
+
+const (
+	stunMagicCookie      = 0x2112A442
+	stunBindingRequest   = 0x0001
+	stunXorMappedAddress = 0x0020
+	stunLegacyMappedAddr = 0x0001
+	stunHeaderLen        = 20
+)
+
+func (r *stunResolver) ResolveIP(ctx context.Context) (string, error) {
+	conn, err := net.Dial("udp", r.server)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	txID := make([]byte, 12)
+	if _, err := rand.Read(txID); err != nil {
+		return "", err
+	}
+
+	request := make([]byte, stunHeaderLen)
+	binary.BigEndian.PutUint16(request[0:2], stunBindingRequest)
+	binary.BigEndian.PutUint16(request[2:4], 0) // message length, no attributes
+	binary.BigEndian.PutUint32(request[4:8], stunMagicCookie)
+	copy(request[8:20], txID)
+
+	if _, err := conn.Write(request); err != nil {
+		return "", err
+	}
+
+	resp := make([]byte, 512)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return "", err
+	}
+	resp = resp[:n]
+
+	return parseStunResponse(resp, txID)
+}
+
+func parseStunResponse(resp, txID []byte) (string, error) {
+	if len(resp) < stunHeaderLen {
+		return "", fmt.Errorf("stun: short response")
+	}
+	msgLen := binary.BigEndian.Uint16(resp[2:4])
+	if int(msgLen)+stunHeaderLen > len(resp) {
+		return "", fmt.Errorf("stun: truncated response")
+	}
+
+	attrs := resp[stunHeaderLen : stunHeaderLen+int(msgLen)]
+	for len(attrs) >= 4 {
+		attrType := binary.BigEndian.Uint16(attrs[0:2])
+		attrLen := binary.BigEndian.Uint16(attrs[2:4])
+		if int(attrLen)+4 > len(attrs) {
+			break
+		}
+		value := attrs[4 : 4+attrLen]
+
+		switch attrType {
+		case stunXorMappedAddress:
+			if ip, err := decodeXorMappedAddress(value, txID); err == nil {
+				return ip, nil
+			}
+		case stunLegacyMappedAddr:
+			if ip, err := decodeMappedAddress(value); err == nil {
+				return ip, nil
+			}
+		}
+
+		// attributes are padded to a multiple of 4 bytes
+		padded := (int(attrLen) + 3) &^ 3
+		attrs = attrs[4+padded:]
+	}
+	return "", fmt.Errorf("stun: no mapped address attribute in response")
+}
+
+func decodeMappedAddress(value []byte) (string, error) {
+	if len(value) < 8 || value[1] != 0x01 {
+		return "", fmt.Errorf("stun: unsupported MAPPED-ADDRESS family")
+	}
+	ip := net.IP(value[4:8])
+	return ip.String(), nil
+}
+
+func decodeXorMappedAddress(value, txID []byte) (string, error) {
+	if len(value) < 8 || value[1] != 0x01 {
+		return "", fmt.Errorf("stun: unsupported XOR-MAPPED-ADDRESS family")
+	}
+
+	cookie := make([]byte, 4)
+	binary.BigEndian.PutUint32(cookie, stunMagicCookie)
+
+	xored := make([]byte, 4)
+	for i := 0; i < 4; i++ {
+		xored[i] = value[4+i] ^ cookie[i]
+	}
+	return net.IP(xored).String(), nil
+}
@@ -0,0 +1,54 @@
+package ipresolver
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestParseStunResponse(t *testing.T) {
+	txID := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+
+	t.Run("xor mapped address", func(t *testing.T) {
+		// family=IPv4, port ignored, address 203.0.113.5 XORed with the magic cookie.
+		cookie := make([]byte, 4)
+		binary.BigEndian.PutUint32(cookie, stunMagicCookie)
+		addr := []byte{203, 0, 113, 5}
+		xored := make([]byte, 4)
+		for i := range addr {
+			xored[i] = addr[i] ^ cookie[i]
+		}
+
+		value := append([]byte{0x00, 0x01, 0x00, 0x00}, xored...)
+		resp := buildStunResponse(txID, stunXorMappedAddress, value)
+
+		got, err := parseStunResponse(resp, txID)
+		if err != nil {
+			t.Fatalf("parseStunResponse() error = %v", err)
+		}
+		if got != "203.0.113.5" {
+			t.Fatalf("parseStunResponse() = %q, want %q", got, "203.0.113.5")
+		}
+	})
+
+	t.Run("no mapped address attribute", func(t *testing.T) {
+		resp := buildStunResponse(txID, 0x9999, []byte{0, 0, 0, 0})
+		if _, err := parseStunResponse(resp, txID); err == nil {
+			t.Fatal("parseStunResponse() error = nil, want error")
+		}
+	})
+}
+
+func buildStunResponse(txID []byte, attrType uint16, value []byte) []byte {
+	attr := make([]byte, 4+len(value))
+	binary.BigEndian.PutUint16(attr[0:2], attrType)
+	binary.BigEndian.PutUint16(attr[2:4], uint16(len(value)))
+	copy(attr[4:], value)
+
+	resp := make([]byte, stunHeaderLen+len(attr))
+	binary.BigEndian.PutUint16(resp[0:2], 0x0101) // binding success response
+	binary.BigEndian.PutUint16(resp[2:4], uint16(len(attr)))
+	binary.BigEndian.PutUint32(resp[4:8], stunMagicCookie)
+	copy(resp[8:20], txID)
+	copy(resp[20:], attr)
+	return resp
+}
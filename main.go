@@ -1,203 +1,88 @@
+// Command update-domains points one or more domains at an IP address,
+// across whichever registrars they're configured to use.
 package main
 
 import (
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
+	"context"
+	"flag"
 	"log"
-	"net/http"
-	"os"
-	"bytes"
-)
-
-type PorkbunAPIResponse struct {
-	Status  string `json:"status"`
-	Message string `json:"message,omitempty"`
-	Domains []struct {
-		Domain string `json:"domain"`
-	} `json:"domains,omitempty"`
-	Records []struct {
-		ID string `json:"id"`
-	} `json:"records,omitempty"`
-}
-
-type PorkbunRecord struct {
-	Name   string `json:"name"`
-	Type   string `json:"type"`
-	Content string `json:"content"`
-	TTL    int    `json:"ttl"`
-	Prio   *int   `json:"prio,omitempty"`
-}
-
-const (
-	PorkbunBaseURL = "https://porkbun.com/api/json/v3"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mikerybka/update-domains/config"
+	"github.com/mikerybka/update-domains/daemon"
+	"github.com/mikerybka/update-domains/ipresolver"
+	"github.com/mikerybka/update-domains/output"
+	"github.com/mikerybka/update-domains/providers"
+	"github.com/mikerybka/update-domains/reconcile"
 )
 
 func main() {
-	if len(os.Args) != 2 {
-		log.Fatalf("Usage: %s <IP_ADDRESS>", os.Args[0])
-	}
-	ipAddress := os.Args[1]
-
-	apiKey := os.Getenv("PORKBUN_API_KEY")
-	secretKey := os.Getenv("PORKBUN_SECRET_KEY")
-
-	if apiKey == "" || secretKey == "" {
-		log.Fatalf("PORKBUN_API_KEY and PORKBUN_SECRET_KEY environment variables must be set")
-	}
-
-	domains, err := getDomains(apiKey, secretKey)
+	configPath := flag.String("config", "domains.json", "path to the domain config file")
+	prune := flag.Bool("prune", false, "delete existing records that are no longer desired (default preserves them)")
+	dryRun := flag.Bool("dry-run", false, "log the planned create/update/delete actions without changing any DNS records")
+	outputFormat := flag.String("output", "text", "how to report actions taken: text or json")
+	runDaemon := flag.Bool("daemon", false, "run continuously, updating only when the public IP changes")
+	interval := flag.Duration("interval", 5*time.Minute, "how often to check the public IP in daemon mode")
+	statePath := flag.String("state-file", "state.json", "where to persist the last-applied IP in daemon mode")
+	resolverSpec := flag.String("ip-resolver", "http:https://ifconfig.me", "how to detect the public IP in daemon mode (http:URL, stun:host:port, or iface:name)")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
 	if err != nil {
-		log.Fatalf("Error retrieving domains: %v", err)
+		log.Fatalf("Error loading config: %v", err)
 	}
 
-	for _, domain := range domains {
-		log.Printf("Processing domain: %s", domain)
-		if err := updateDomainRecords(domain, ipAddress, apiKey, secretKey); err != nil {
-			log.Printf("Error updating domain %s: %v", domain, err)
-		}
+	reconcileOpts := reconcile.Options{
+		DryRun: *dryRun,
+		Report: output.NewReporter(*outputFormat),
 	}
-}
 
-func getDomains(apiKey, secretKey string) ([]string, error) {
-	url := fmt.Sprintf("%s/domains/retrieve", PorkbunBaseURL)
-	requestBody, _ := json.Marshal(map[string]string{
-		"apikey":    apiKey,
-		"secretkey": secretKey,
-	})
-
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(requestBody))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, _ := ioutil.ReadAll(resp.Body)
-	var response PorkbunAPIResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, err
-	}
-
-	if response.Status != "SUCCESS" {
-		return nil, fmt.Errorf("API error: %s", response.Message)
-	}
-
-	domains := []string{}
-	for _, domain := range response.Domains {
-		domains = append(domains, domain.Domain)
-	}
-	return domains, nil
-}
-
-func updateDomainRecords(domain, ipAddress, apiKey, secretKey string) error {
-	records, err := getDomainRecords(domain, apiKey, secretKey)
-	if err != nil {
-		return err
-	}
-
-	for _, record := range records {
-		if err := deleteDomainRecord(domain, record, apiKey, secretKey); err != nil {
-			return err
+	if *runDaemon {
+		if flag.NArg() != 0 {
+			log.Fatalf("Usage: %s -daemon [flags] (no IP address argument)", flag.CommandLine.Name())
 		}
-	}
-
-	newRecords := []PorkbunRecord{
-		{Name: "@", Type: "A", Content: ipAddress, TTL: 300},
-		{Name: "*", Type: "A", Content: ipAddress, TTL: 300},
-	}
 
-	for _, record := range newRecords {
-		if err := createDomainRecord(domain, record, apiKey, secretKey); err != nil {
-			return err
+		resolver, err := ipresolver.New(*resolverSpec)
+		if err != nil {
+			log.Fatalf("Error configuring IP resolver: %v", err)
 		}
-	}
 
-	return nil
-}
-
-func getDomainRecords(domain, apiKey, secretKey string) ([]string, error) {
-	url := fmt.Sprintf("%s/dns/retrieve/%s", PorkbunBaseURL, domain)
-	requestBody, _ := json.Marshal(map[string]string{
-		"apikey":    apiKey,
-		"secretkey": secretKey,
-	})
-
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(requestBody))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, _ := ioutil.ReadAll(resp.Body)
-	var response PorkbunAPIResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, err
-	}
-
-	if response.Status != "SUCCESS" {
-		return nil, fmt.Errorf("API error: %s", response.Message)
-	}
-
-	recordIDs := []string{}
-	for _, record := range response.Records {
-		recordIDs = append(recordIDs, record.ID)
-	}
-	return recordIDs, nil
-}
-
-func deleteDomainRecord(domain, recordID, apiKey, secretKey string) error {
-	url := fmt.Sprintf("%s/dns/delete/%s/%s", PorkbunBaseURL, domain, recordID)
-	requestBody, _ := json.Marshal(map[string]string{
-		"apikey":    apiKey,
-		"secretkey": secretKey,
-	})
-
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(requestBody))
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	body, _ := ioutil.ReadAll(resp.Body)
-	var response PorkbunAPIResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return err
-	}
-
-	if response.Status != "SUCCESS" {
-		return fmt.Errorf("API error: %s", response.Message)
-	}
-
-	return nil
-}
-
-func createDomainRecord(domain string, record PorkbunRecord, apiKey, secretKey string) error {
-	url := fmt.Sprintf("%s/dns/create/%s", PorkbunBaseURL, domain)
-	recordData := map[string]interface{}{
-		"apikey":    apiKey,
-		"secretkey": secretKey,
-		"name":      record.Name,
-		"type":      record.Type,
-		"content":   record.Content,
-		"ttl":       record.TTL,
-	}
-	requestBody, _ := json.Marshal(recordData)
-
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(requestBody))
-	if err != nil {
-		return err
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		err = daemon.Run(ctx, daemon.Config{
+			Resolver:   resolver,
+			Domains:    cfg,
+			StatePath:  *statePath,
+			Interval:   *interval,
+			Prune:      *prune,
+			MinBackoff: time.Second,
+			MaxBackoff: 5 * time.Minute,
+			Reconcile:  reconcileOpts,
+		})
+		if err != nil && err != context.Canceled {
+			log.Fatalf("daemon exited: %v", err)
+		}
+		return
 	}
-	defer resp.Body.Close()
 
-	body, _ := ioutil.ReadAll(resp.Body)
-	var response PorkbunAPIResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return err
+	if flag.NArg() != 1 {
+		log.Fatalf("Usage: %s [-config path] [-prune] [-dry-run] [-output text|json] <IP_ADDRESS>", flag.CommandLine.Name())
 	}
+	ipAddress := flag.Arg(0)
 
-	if response.Status != "SUCCESS" {
-		return fmt.Errorf("API error: %s", response.Message)
+	ctx := context.Background()
+	for domain, domainCfg := range cfg.Domains {
+		log.Printf("Processing domain: %s", domain)
+		provider, err := providers.New(domainCfg.Provider, domainCfg.Credentials)
+		if err != nil {
+			log.Printf("Error updating domain %s: %v", domain, err)
+			continue
+		}
+		if err := reconcile.UpdateDomain(ctx, provider, domain, ipAddress, domainCfg, *prune, reconcileOpts); err != nil {
+			log.Printf("Error updating domain %s: %v", domain, err)
+		}
 	}
-
-	return nil
 }
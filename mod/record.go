@@ -0,0 +1,20 @@
+// Package mod holds the types shared by every DNS provider implementation.
+package mod
+
+// Record is a single DNS record, in a form generic enough to round-trip
+// through any of the supported providers.
+type Record struct {
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	TTL     int    `json:"ttl"`
+	Prio    *int   `json:"prio,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+// IPSetRequest describes a request to point a domain at a given IP address.
+type IPSetRequest struct {
+	Domain string `json:"domain"`
+	IP     string `json:"ip"`
+}
@@ -0,0 +1,38 @@
+// Package output formats the actions reconcile.Apply takes (or, in
+// dry-run mode, plans) for either human or machine consumption.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mikerybka/update-domains/reconcile"
+)
+
+// NewReporter returns a reconcile.Reporter that prints each action to
+// stdout in the given format ("text" or "json"). An unrecognized format
+// falls back to "text".
+func NewReporter(format string) reconcile.Reporter {
+	if format == "json" {
+		return func(a reconcile.Action) {
+			b, err := json.Marshal(a)
+			if err != nil {
+				fmt.Printf(`{"domain":%q,"action":%q,"status":"error","error":%q}`+"\n", a.Domain, a.Action, err.Error())
+				return
+			}
+			fmt.Println(string(b))
+		}
+	}
+
+	return func(a reconcile.Action) {
+		verb := a.Action
+		if a.Status == "planned" {
+			verb = "would " + verb
+		}
+		if a.Error != "" {
+			fmt.Printf("%s: %s %s %s (%s): %s\n", a.Domain, verb, a.Type, a.Name, a.RecordID, a.Error)
+			return
+		}
+		fmt.Printf("%s: %s %s %s (%s)\n", a.Domain, verb, a.Type, a.Name, a.RecordID)
+	}
+}
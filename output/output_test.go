@@ -0,0 +1,61 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mikerybka/update-domains/reconcile"
+)
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() error = %v", err)
+	}
+
+	orig := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	fn()
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("io.ReadAll() error = %v", err)
+	}
+	return string(out)
+}
+
+func TestJSONReporterEmitsOneLinePerAction(t *testing.T) {
+	action := reconcile.Action{Domain: "example.com", Action: "delete", RecordID: "1", Status: "ok"}
+
+	got := captureStdout(t, func() {
+		NewReporter("json")(action)
+	})
+
+	var decoded reconcile.Action
+	if err := json.Unmarshal([]byte(strings.TrimSpace(got)), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v (%q)", err, got)
+	}
+	if decoded != action {
+		t.Fatalf("decoded action = %+v, want %+v", decoded, action)
+	}
+}
+
+func TestTextReporterMarksPlannedActions(t *testing.T) {
+	action := reconcile.Action{Domain: "example.com", Action: "create", Status: "planned"}
+
+	got := captureStdout(t, func() {
+		NewReporter("text")(action)
+	})
+
+	if !strings.Contains(got, "would create") {
+		t.Fatalf("text output = %q, want it to mention the planned action", got)
+	}
+}
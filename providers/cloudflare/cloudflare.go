@@ -0,0 +1,212 @@
+// Package cloudflare implements the providers.Provider interface against
+// the Cloudflare API (https://developers.cloudflare.com/api/).
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/mikerybka/update-domains/mod"
+)
+
+// BaseURL is the Cloudflare API root. It is a var so tests can point it at
+// an httptest.Server.
+var BaseURL = "https://api.cloudflare.com/client/v4"
+
+type apiResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+	Result json.RawMessage `json:"result"`
+}
+
+type zone struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type record struct {
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	TTL     int    `json:"ttl,omitempty"`
+	Prio    *int   `json:"priority,omitempty"`
+}
+
+// Provider talks to the Cloudflare API on behalf of a single account,
+// authenticated with an API token.
+type Provider struct {
+	apiToken string
+	client   *http.Client
+}
+
+// New returns a Provider authenticated with the given Cloudflare API token.
+func New(apiToken string) *Provider {
+	return &Provider{apiToken: apiToken, client: http.DefaultClient}
+}
+
+func (p *Provider) do(ctx context.Context, method, path string, body interface{}) (*apiResponse, error) {
+	var reader *bytes.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(b)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, BaseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var response apiResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, err
+	}
+	if !response.Success {
+		msg := "unknown error"
+		if len(response.Errors) > 0 {
+			msg = response.Errors[0].Message
+		}
+		return nil, fmt.Errorf("API error: %s", msg)
+	}
+	return &response, nil
+}
+
+// zoneID looks up the Cloudflare zone id for a domain name.
+func (p *Provider) zoneID(ctx context.Context, domain string) (string, error) {
+	response, err := p.do(ctx, http.MethodGet, "/zones?name="+domain, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var zones []zone
+	if err := json.Unmarshal(response.Result, &zones); err != nil {
+		return "", err
+	}
+	if len(zones) == 0 {
+		return "", fmt.Errorf("no zone found for domain %q", domain)
+	}
+	return zones[0].ID, nil
+}
+
+// ListDomains returns every zone name in the account.
+func (p *Provider) ListDomains(ctx context.Context) ([]string, error) {
+	response, err := p.do(ctx, http.MethodGet, "/zones", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var zones []zone
+	if err := json.Unmarshal(response.Result, &zones); err != nil {
+		return nil, err
+	}
+
+	domains := []string{}
+	for _, z := range zones {
+		domains = append(domains, z.Name)
+	}
+	return domains, nil
+}
+
+// ListRecords returns every DNS record for domain.
+func (p *Provider) ListRecords(ctx context.Context, domain string) ([]mod.Record, error) {
+	zoneID, err := p.zoneID(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := p.do(ctx, http.MethodGet, fmt.Sprintf("/zones/%s/dns_records", zoneID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []record
+	if err := json.Unmarshal(response.Result, &records); err != nil {
+		return nil, err
+	}
+
+	out := []mod.Record{}
+	for _, r := range records {
+		out = append(out, mod.Record{ID: r.ID, Name: r.Name, Type: r.Type, Content: r.Content, TTL: r.TTL, Prio: r.Prio})
+	}
+	return out, nil
+}
+
+// CreateRecord adds a new DNS record to domain.
+func (p *Provider) CreateRecord(ctx context.Context, domain string, r mod.Record) error {
+	zoneID, err := p.zoneID(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.do(ctx, http.MethodPost, fmt.Sprintf("/zones/%s/dns_records", zoneID), record{
+		Name:    qualifyName(r.Name, domain),
+		Type:    r.Type,
+		Content: r.Content,
+		TTL:     r.TTL,
+		Prio:    r.Prio,
+	})
+	return err
+}
+
+// UpdateRecord replaces the record with the given id.
+func (p *Provider) UpdateRecord(ctx context.Context, domain, id string, r mod.Record) error {
+	zoneID, err := p.zoneID(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.do(ctx, http.MethodPatch, fmt.Sprintf("/zones/%s/dns_records/%s", zoneID, id), record{
+		Name:    qualifyName(r.Name, domain),
+		Type:    r.Type,
+		Content: r.Content,
+		TTL:     r.TTL,
+		Prio:    r.Prio,
+	})
+	return err
+}
+
+// qualifyName converts name - our canonical "@" for the apex, or a name
+// already in the form Cloudflare itself returns (the zone name for the
+// apex, or a subdomain) - into the form the Cloudflare API requires:
+// the zone name for the apex, since Cloudflare has no "@" shorthand.
+func qualifyName(name, domain string) string {
+	if name == "@" || name == "" {
+		return domain
+	}
+	return name
+}
+
+// DeleteRecord removes the record with the given id from domain.
+func (p *Provider) DeleteRecord(ctx context.Context, domain, id string) error {
+	zoneID, err := p.zoneID(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.do(ctx, http.MethodDelete, fmt.Sprintf("/zones/%s/dns_records/%s", zoneID, id), nil)
+	return err
+}
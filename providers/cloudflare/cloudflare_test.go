@@ -0,0 +1,96 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mikerybka/update-domains/mod"
+)
+
+func withTestServer(t *testing.T, handler http.HandlerFunc) *Provider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	orig := BaseURL
+	BaseURL = server.URL
+	t.Cleanup(func() { BaseURL = orig })
+
+	return New("token")
+}
+
+func TestListRecords(t *testing.T) {
+	tests := []struct {
+		name    string
+		routes  map[string]string
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "success",
+			routes: map[string]string{
+				"/zones":                   `{"success":true,"result":[{"id":"zone1","name":"example.com"}]}`,
+				"/zones/zone1/dns_records": `{"success":true,"result":[{"id":"rec1","name":"example.com","type":"A","content":"1.2.3.4","ttl":300}]}`,
+			},
+			want: 1,
+		},
+		{
+			name: "zone lookup error",
+			routes: map[string]string{
+				"/zones": `{"success":false,"errors":[{"message":"invalid token"}]}`,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				body, ok := tt.routes[r.URL.Path]
+				if !ok {
+					t.Errorf("unexpected path: %s", r.URL.Path)
+					return
+				}
+				w.Write([]byte(body))
+			})
+
+			got, err := p.ListRecords(context.Background(), "example.com")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListRecords() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != tt.want {
+				t.Fatalf("ListRecords() = %v records, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateRecordQualifiesApex(t *testing.T) {
+	var sent record
+	p := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/zones":
+			w.Write([]byte(`{"success":true,"result":[{"id":"zone1","name":"example.com"}]}`))
+		case r.URL.Path == "/zones/zone1/dns_records":
+			json.NewDecoder(r.Body).Decode(&sent)
+			w.Write([]byte(`{"success":true,"result":{}}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	})
+
+	if err := p.CreateRecord(context.Background(), "example.com", mod.Record{
+		Name: "@", Type: "A", Content: "1.2.3.4", TTL: 300,
+	}); err != nil {
+		t.Fatalf("CreateRecord() error = %v", err)
+	}
+	if sent.Name != "example.com" {
+		t.Errorf("CreateRecord() sent Name = %q, want the zone name %q", sent.Name, "example.com")
+	}
+}
@@ -0,0 +1,150 @@
+// Package gandi implements the providers.Provider interface against the
+// Gandi LiveDNS API (https://api.gandi.net/docs/livedns/).
+package gandi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/mikerybka/update-domains/mod"
+)
+
+// BaseURL is the Gandi LiveDNS API root. It is a var so tests can point it
+// at an httptest.Server.
+var BaseURL = "https://api.gandi.net/v5/livedns"
+
+type record struct {
+	ID          string   `json:"id,omitempty"`
+	RRSetName   string   `json:"rrset_name"`
+	RRSetType   string   `json:"rrset_type"`
+	RRSetTTL    int      `json:"rrset_ttl"`
+	RRSetValues []string `json:"rrset_values"`
+}
+
+type domain struct {
+	FQDN string `json:"fqdn"`
+}
+
+// Provider talks to the Gandi LiveDNS API on behalf of a single account.
+type Provider struct {
+	apiKey string
+	client *http.Client
+}
+
+// New returns a Provider authenticated with the given Gandi API key.
+func New(apiKey string) *Provider {
+	return &Provider{apiKey: apiKey, client: http.DefaultClient}
+}
+
+func (p *Provider) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Apikey "+p.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("API error: %s: %s", resp.Status, string(respBody))
+	}
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	return json.Unmarshal(respBody, out)
+}
+
+// ListDomains returns every domain in the account.
+func (p *Provider) ListDomains(ctx context.Context) ([]string, error) {
+	var domains []domain
+	if err := p.do(ctx, http.MethodGet, "/domains", nil, &domains); err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, d := range domains {
+		names = append(names, d.FQDN)
+	}
+	return names, nil
+}
+
+// ListRecords returns every DNS record for domain.
+func (p *Provider) ListRecords(ctx context.Context, domain string) ([]mod.Record, error) {
+	var records []record
+	if err := p.do(ctx, http.MethodGet, fmt.Sprintf("/domains/%s/records", domain), nil, &records); err != nil {
+		return nil, err
+	}
+
+	out := []mod.Record{}
+	for _, r := range records {
+		out = append(out, fromGandiRecord(r))
+	}
+	return out, nil
+}
+
+// CreateRecord adds a new DNS record to domain.
+func (p *Provider) CreateRecord(ctx context.Context, domain string, r mod.Record) error {
+	return p.do(ctx, http.MethodPost, fmt.Sprintf("/domains/%s/records", domain), toGandiRecord(r), nil)
+}
+
+// UpdateRecord replaces the record with the given id. Gandi identifies
+// records by "name/type" rather than a synthetic id, so id must be in that
+// form.
+func (p *Provider) UpdateRecord(ctx context.Context, domain, id string, r mod.Record) error {
+	return p.do(ctx, http.MethodPut, fmt.Sprintf("/domains/%s/records/%s", domain, id), toGandiRecord(r), nil)
+}
+
+// DeleteRecord removes the record with the given id from domain. Gandi
+// identifies records by "name/type" rather than a synthetic id, so id must
+// be in that form.
+func (p *Provider) DeleteRecord(ctx context.Context, domain, id string) error {
+	return p.do(ctx, http.MethodDelete, fmt.Sprintf("/domains/%s/records/%s", domain, id), nil, nil)
+}
+
+func fromGandiRecord(r record) mod.Record {
+	content := ""
+	if len(r.RRSetValues) > 0 {
+		content = r.RRSetValues[0]
+	}
+	return mod.Record{
+		ID:      fmt.Sprintf("%s/%s", r.RRSetName, r.RRSetType),
+		Name:    r.RRSetName,
+		Type:    r.RRSetType,
+		Content: content,
+		TTL:     r.RRSetTTL,
+	}
+}
+
+func toGandiRecord(r mod.Record) record {
+	return record{
+		RRSetName:   r.Name,
+		RRSetType:   r.Type,
+		RRSetTTL:    r.TTL,
+		RRSetValues: []string{r.Content},
+	}
+}
@@ -0,0 +1,110 @@
+package gandi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mikerybka/update-domains/mod"
+)
+
+func withTestServer(t *testing.T, handler http.HandlerFunc) *Provider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	orig := BaseURL
+	BaseURL = server.URL
+	t.Cleanup(func() { BaseURL = orig })
+
+	return New("key")
+}
+
+func TestListRecords(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    int
+		wantErr bool
+	}{
+		{
+			name:   "success",
+			status: http.StatusOK,
+			body:   `[{"rrset_name":"@","rrset_type":"A","rrset_ttl":300,"rrset_values":["1.2.3.4"]}]`,
+			want:   1,
+		},
+		{
+			name:    "api error",
+			status:  http.StatusUnauthorized,
+			body:    `{"message":"invalid key"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/domains/example.com/records" {
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			got, err := p.ListRecords(context.Background(), "example.com")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListRecords() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != tt.want {
+				t.Fatalf("ListRecords() = %v records, want %d", got, tt.want)
+			}
+			if got[0].Name != "@" || got[0].Content != "1.2.3.4" {
+				t.Errorf("ListRecords() = %+v, want apex record with content 1.2.3.4", got[0])
+			}
+		})
+	}
+}
+
+func TestCreateAndDeleteRecord(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		status  int
+		wantErr bool
+	}{
+		{name: "create success", method: http.MethodPost, path: "/domains/example.com/records", status: http.StatusCreated},
+		{name: "create error", method: http.MethodPost, path: "/domains/example.com/records", status: http.StatusBadRequest, wantErr: true},
+		{name: "delete success", method: http.MethodDelete, path: "/domains/example.com/records/www/A", status: http.StatusNoContent},
+		{name: "delete error", method: http.MethodDelete, path: "/domains/example.com/records/www/A", status: http.StatusNotFound, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tt.method || r.URL.Path != tt.path {
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+			})
+
+			var err error
+			if tt.method == http.MethodPost {
+				err = p.CreateRecord(context.Background(), "example.com", mod.Record{
+					Name: "@", Type: "A", Content: "1.2.3.4", TTL: 300,
+				})
+			} else {
+				err = p.DeleteRecord(context.Background(), "example.com", "www/A")
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
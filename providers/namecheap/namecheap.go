@@ -0,0 +1,250 @@
+// Package namecheap implements the providers.Provider interface against the
+// Namecheap API (https://www.namecheap.com/support/api/methods/).
+//
+// Namecheap has no per-record create/delete call: the DNS host records for
+// a domain are always read and written as a complete set via
+// namecheap.domains.dns.getHosts / setHosts. CreateRecord and DeleteRecord
+// below fetch the current set, apply the change, and write the whole set
+// back.
+package namecheap
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mikerybka/update-domains/mod"
+)
+
+// BaseURL is the Namecheap API root. It is a var so tests can point it at an
+// httptest.Server.
+var BaseURL = "https://api.namecheap.com/xml.response"
+
+type apiResponse struct {
+	XMLName xml.Name `xml:"ApiResponse"`
+	Status  string   `xml:"Status,attr"`
+	Errors  struct {
+		Error []string `xml:"Error"`
+	} `xml:"Errors"`
+	CommandResponse struct {
+		DomainDNSGetHostsResult struct {
+			Hosts []host `xml:"host"`
+		} `xml:"DomainDNSGetHostsResult"`
+		DomainGetListResult struct {
+			Domains []struct {
+				Name string `xml:"Name,attr"`
+			} `xml:"Domain"`
+		} `xml:"DomainGetListResult"`
+	} `xml:"CommandResponse"`
+}
+
+type host struct {
+	HostID  string `xml:"HostId,attr"`
+	Name    string `xml:"Name,attr"`
+	Type    string `xml:"Type,attr"`
+	Address string `xml:"Address,attr"`
+	TTL     string `xml:"TTL,attr"`
+	MXPref  string `xml:"MXPref,attr"`
+}
+
+// Provider talks to the Namecheap API on behalf of a single account.
+type Provider struct {
+	apiUser  string
+	apiKey   string
+	username string
+	clientIP string
+	client   *http.Client
+}
+
+// New returns a Provider authenticated with the given Namecheap credentials.
+// clientIP must be an IP address whitelisted on the Namecheap account, as
+// required by their API.
+func New(apiUser, apiKey, username, clientIP string) *Provider {
+	return &Provider{
+		apiUser:  apiUser,
+		apiKey:   apiKey,
+		username: username,
+		clientIP: clientIP,
+		client:   http.DefaultClient,
+	}
+}
+
+func (p *Provider) get(ctx context.Context, command string, extra url.Values) (*apiResponse, error) {
+	q := url.Values{}
+	if extra != nil {
+		q = extra
+	}
+	q.Set("ApiUser", p.apiUser)
+	q.Set("ApiKey", p.apiKey)
+	q.Set("UserName", p.username)
+	q.Set("ClientIp", p.clientIP)
+	q.Set("Command", command)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, BaseURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var response apiResponse
+	if err := xml.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+	if response.Status != "OK" {
+		return nil, fmt.Errorf("API error: %s", strings.Join(response.Errors.Error, "; "))
+	}
+	return &response, nil
+}
+
+// ListDomains returns every domain in the account.
+func (p *Provider) ListDomains(ctx context.Context) ([]string, error) {
+	response, err := p.get(ctx, "namecheap.domains.getList", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	domains := []string{}
+	for _, d := range response.CommandResponse.DomainGetListResult.Domains {
+		domains = append(domains, d.Name)
+	}
+	return domains, nil
+}
+
+// ListRecords returns every DNS host record for domain.
+func (p *Provider) ListRecords(ctx context.Context, domain string) ([]mod.Record, error) {
+	hosts, err := p.getHosts(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []mod.Record{}
+	for _, h := range hosts {
+		records = append(records, fromHost(h))
+	}
+	return records, nil
+}
+
+// CreateRecord adds a new DNS record to domain.
+func (p *Provider) CreateRecord(ctx context.Context, domain string, r mod.Record) error {
+	hosts, err := p.getHosts(ctx, domain)
+	if err != nil {
+		return err
+	}
+	hosts = append(hosts, toHost(r))
+	return p.setHosts(ctx, domain, hosts)
+}
+
+// UpdateRecord edits the record with the given id in place. Namecheap has
+// no partial update call, so this rewrites the whole host list with the
+// matching entry replaced.
+func (p *Provider) UpdateRecord(ctx context.Context, domain, id string, r mod.Record) error {
+	hosts, err := p.getHosts(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	for i, h := range hosts {
+		if h.HostID == id {
+			updated := toHost(r)
+			updated.HostID = id
+			hosts[i] = updated
+			return p.setHosts(ctx, domain, hosts)
+		}
+	}
+	return fmt.Errorf("no host found with id %q", id)
+}
+
+// DeleteRecord removes the record with the given id from domain.
+func (p *Provider) DeleteRecord(ctx context.Context, domain, id string) error {
+	hosts, err := p.getHosts(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	kept := hosts[:0]
+	for _, h := range hosts {
+		if h.HostID != id {
+			kept = append(kept, h)
+		}
+	}
+	return p.setHosts(ctx, domain, kept)
+}
+
+func (p *Provider) getHosts(ctx context.Context, domain string) ([]host, error) {
+	sld, tld := splitDomain(domain)
+	q := url.Values{"SLD": {sld}, "TLD": {tld}}
+	response, err := p.get(ctx, "namecheap.domains.dns.getHosts", q)
+	if err != nil {
+		return nil, err
+	}
+	return response.CommandResponse.DomainDNSGetHostsResult.Hosts, nil
+}
+
+func (p *Provider) setHosts(ctx context.Context, domain string, hosts []host) error {
+	sld, tld := splitDomain(domain)
+	q := url.Values{"SLD": {sld}, "TLD": {tld}}
+	for i, h := range hosts {
+		n := fmt.Sprintf("%d", i+1)
+		q.Set("HostName"+n, h.Name)
+		q.Set("RecordType"+n, h.Type)
+		q.Set("Address"+n, h.Address)
+		q.Set("TTL"+n, h.TTL)
+		if h.MXPref != "" {
+			q.Set("MXPref"+n, h.MXPref)
+		}
+	}
+	_, err := p.get(ctx, "namecheap.domains.dns.setHosts", q)
+	return err
+}
+
+func splitDomain(domain string) (sld, tld string) {
+	parts := strings.SplitN(domain, ".", 2)
+	if len(parts) != 2 {
+		return domain, ""
+	}
+	return parts[0], parts[1]
+}
+
+func fromHost(h host) mod.Record {
+	out := mod.Record{
+		ID:      h.HostID,
+		Name:    h.Name,
+		Type:    h.Type,
+		Content: h.Address,
+	}
+	fmt.Sscanf(h.TTL, "%d", &out.TTL)
+	if h.MXPref != "" {
+		var prio int
+		fmt.Sscanf(h.MXPref, "%d", &prio)
+		out.Prio = &prio
+	}
+	return out
+}
+
+func toHost(r mod.Record) host {
+	h := host{
+		HostID:  r.ID,
+		Name:    r.Name,
+		Type:    r.Type,
+		Address: r.Content,
+		TTL:     fmt.Sprintf("%d", r.TTL),
+	}
+	if r.Prio != nil {
+		h.MXPref = fmt.Sprintf("%d", *r.Prio)
+	}
+	return h
+}
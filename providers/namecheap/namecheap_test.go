@@ -0,0 +1,79 @@
+package namecheap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mikerybka/update-domains/mod"
+)
+
+func withTestServer(t *testing.T, handler http.HandlerFunc) *Provider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	orig := BaseURL
+	BaseURL = server.URL
+	t.Cleanup(func() { BaseURL = orig })
+
+	return New("apiuser", "apikey", "user", "1.2.3.4")
+}
+
+const getHostsBody = `<?xml version="1.0"?><ApiResponse Status="OK"><CommandResponse>` +
+	`<DomainDNSGetHostsResult><host HostId="1" Name="@" Type="A" Address="1.2.3.4" TTL="300"/></DomainDNSGetHostsResult>` +
+	`</CommandResponse></ApiResponse>`
+
+func TestListRecords(t *testing.T) {
+	p := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("Command") != "namecheap.domains.dns.getHosts" {
+			t.Errorf("unexpected command: %s", r.URL.Query().Get("Command"))
+		}
+		w.Write([]byte(getHostsBody))
+	})
+
+	got, err := p.ListRecords(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("ListRecords() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "@" || got[0].TTL != 300 {
+		t.Fatalf("ListRecords() = %+v, want a single apex record with TTL 300", got)
+	}
+}
+
+func TestCreateRecordWritesWholeHostSet(t *testing.T) {
+	var sawNewHost bool
+	p := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("Command") {
+		case "namecheap.domains.dns.getHosts":
+			w.Write([]byte(getHostsBody))
+		case "namecheap.domains.dns.setHosts":
+			if r.URL.Query().Get("HostName2") == "www" {
+				sawNewHost = true
+			}
+			w.Write([]byte(`<?xml version="1.0"?><ApiResponse Status="OK"><CommandResponse/></ApiResponse>`))
+		default:
+			t.Errorf("unexpected command: %s", r.URL.Query().Get("Command"))
+		}
+	})
+
+	if err := p.CreateRecord(context.Background(), "example.com", mod.Record{
+		Name: "www", Type: "A", Content: "1.2.3.4", TTL: 300,
+	}); err != nil {
+		t.Fatalf("CreateRecord() error = %v", err)
+	}
+	if !sawNewHost {
+		t.Error("CreateRecord() did not include the new host in setHosts")
+	}
+}
+
+func TestAPIErrorIsSurfaced(t *testing.T) {
+	p := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<?xml version="1.0"?><ApiResponse Status="ERROR"><Errors><Error>Invalid API key</Error></Errors></ApiResponse>`))
+	})
+
+	if _, err := p.ListRecords(context.Background(), "example.com"); err == nil {
+		t.Fatal("ListRecords() error = nil, want an error for an ERROR status response")
+	}
+}
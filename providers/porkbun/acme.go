@@ -0,0 +1,91 @@
+package porkbun
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mikerybka/update-domains/mod"
+)
+
+// acmeChallengeTTL is kept short since the record only needs to live for
+// the duration of a single certificate issuance.
+const acmeChallengeTTL = 120
+
+// acmePollInterval and acmePollTimeout bound how long PresentTXT waits for
+// Porkbun to actually serve the record it just created, since DNS-01
+// validation will fail if the CA queries before propagation finishes.
+const (
+	acmePollInterval = 5 * time.Second
+	acmePollTimeout  = 2 * time.Minute
+)
+
+// PresentTXT creates a TXT record for the ACME DNS-01 challenge at fqdn
+// (e.g. "_acme-challenge.example.com.") with the given value, and waits
+// until Porkbun reports it before returning. This lets Provider act as a
+// challenge.Provider for go-acme/lego.
+func (p *Provider) PresentTXT(domain, fqdn, value string) error {
+	name := challengeName(domain, fqdn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), acmePollTimeout)
+	defer cancel()
+
+	if err := p.CreateRecord(ctx, domain, mod.Record{
+		Name:    name,
+		Type:    "TXT",
+		Content: value,
+		TTL:     acmeChallengeTTL,
+	}); err != nil {
+		return fmt.Errorf("presenting ACME challenge for %s: %w", fqdn, err)
+	}
+
+	for {
+		records, err := p.ListRecords(ctx, domain)
+		if err != nil {
+			return fmt.Errorf("polling for ACME challenge record: %w", err)
+		}
+		for _, r := range records {
+			if r.Type == "TXT" && r.Name == name && r.Content == value {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for ACME challenge record to appear: %w", ctx.Err())
+		case <-time.After(acmePollInterval):
+		}
+	}
+}
+
+// CleanupTXT removes the TXT record created by PresentTXT for fqdn.
+func (p *Provider) CleanupTXT(domain, fqdn string) error {
+	name := challengeName(domain, fqdn)
+
+	ctx := context.Background()
+	records, err := p.ListRecords(ctx, domain)
+	if err != nil {
+		return fmt.Errorf("cleaning up ACME challenge for %s: %w", fqdn, err)
+	}
+
+	for _, r := range records {
+		if r.Type == "TXT" && r.Name == name {
+			if err := p.DeleteRecord(ctx, domain, r.ID); err != nil {
+				return fmt.Errorf("cleaning up ACME challenge for %s: %w", fqdn, err)
+			}
+		}
+	}
+	return nil
+}
+
+// challengeName turns the fully-qualified challenge name lego hands us
+// into the bare record name Porkbun expects, relative to domain.
+func challengeName(domain, fqdn string) string {
+	name := strings.TrimSuffix(fqdn, ".")
+	name = strings.TrimSuffix(name, "."+domain)
+	if name == domain {
+		return "@"
+	}
+	return name
+}
@@ -0,0 +1,76 @@
+package porkbun
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestChallengeName(t *testing.T) {
+	tests := []struct {
+		name   string
+		domain string
+		fqdn   string
+		want   string
+	}{
+		{
+			name:   "root domain challenge",
+			domain: "example.com",
+			fqdn:   "_acme-challenge.example.com.",
+			want:   "_acme-challenge",
+		},
+		{
+			name:   "subdomain challenge",
+			domain: "example.com",
+			fqdn:   "_acme-challenge.sub.example.com.",
+			want:   "_acme-challenge.sub",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := challengeName(tt.domain, tt.fqdn); got != tt.want {
+				t.Errorf("challengeName(%q, %q) = %q, want %q", tt.domain, tt.fqdn, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPresentAndCleanupTXT(t *testing.T) {
+	records := map[string]string{} // name -> content
+
+	p := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/dns/create/example.com":
+			records["_acme-challenge"] = "challenge-value"
+			w.Write([]byte(`{"status":"SUCCESS"}`))
+		case r.URL.Path == "/dns/retrieve/example.com":
+			// Porkbun's retrieve endpoint reports the name as the FQDN, not
+			// the bare subdomain challengeName produces - ListRecords must
+			// normalize it before PresentTXT/CleanupTXT compare against it.
+			if content, ok := records["_acme-challenge"]; ok {
+				w.Write([]byte(`{"status":"SUCCESS","records":[{"id":"1","name":"_acme-challenge.example.com","type":"TXT","content":"` + content + `","ttl":"120"}]}`))
+				return
+			}
+			w.Write([]byte(`{"status":"SUCCESS","records":[]}`))
+		case r.URL.Path == "/dns/delete/example.com/1":
+			delete(records, "_acme-challenge")
+			w.Write([]byte(`{"status":"SUCCESS"}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	})
+
+	if err := p.PresentTXT("example.com", "_acme-challenge.example.com.", "challenge-value"); err != nil {
+		t.Fatalf("PresentTXT() error = %v", err)
+	}
+	if _, ok := records["_acme-challenge"]; !ok {
+		t.Fatal("PresentTXT() did not create the challenge record")
+	}
+
+	if err := p.CleanupTXT("example.com", "_acme-challenge.example.com."); err != nil {
+		t.Fatalf("CleanupTXT() error = %v", err)
+	}
+	if _, ok := records["_acme-challenge"]; ok {
+		t.Fatal("CleanupTXT() did not remove the challenge record")
+	}
+}
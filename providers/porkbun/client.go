@@ -0,0 +1,98 @@
+package porkbun
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mikerybka/update-domains/backoff"
+)
+
+// minRequestInterval is the gap enforced between outgoing requests.
+// Porkbun rejects bursts of calls with "unable to create the DNS record",
+// so callers are serialized with a minimum spacing rather than left to
+// race each other.
+const minRequestInterval = 500 * time.Millisecond
+
+const maxRetries = 5
+
+// rateLimitedClient wraps an *http.Client to enforce minRequestInterval
+// between requests and retry on 429/5xx with exponential backoff.
+type rateLimitedClient struct {
+	http *http.Client
+
+	mu          sync.Mutex
+	lastRequest time.Time
+}
+
+func newRateLimitedClient() *rateLimitedClient {
+	return &rateLimitedClient{http: http.DefaultClient}
+}
+
+// Do sends req, retrying on rate-limit and server errors. req.Body must
+// support GetBody (true of the *bytes.Buffer bodies this package builds)
+// so it can be resent on retry.
+func (c *rateLimitedClient) Do(req *http.Request) (*http.Response, error) {
+	bo := backoff.New(minRequestInterval, 30*time.Second)
+
+	for attempt := 0; ; attempt++ {
+		if err := c.throttle(req.Context()); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.http.Do(req)
+		if err == nil && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return resp, nil
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		if attempt >= maxRetries {
+			if err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("giving up after %d retries: %s", attempt, resp.Status)
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(bo.Next()):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+func (c *rateLimitedClient) throttle(ctx context.Context) error {
+	c.mu.Lock()
+	now := time.Now()
+	wait := minRequestInterval - now.Sub(c.lastRequest)
+	if wait < 0 {
+		wait = 0
+	}
+	// Record when this request will actually go out, so the next caller
+	// is spaced from the send rather than from this call.
+	c.lastRequest = now.Add(wait)
+	c.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
@@ -0,0 +1,238 @@
+// Package porkbun implements the providers.Provider interface against the
+// Porkbun DNS API (https://porkbun.com/api/json/v3/documentation).
+package porkbun
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mikerybka/update-domains/backoff"
+	"github.com/mikerybka/update-domains/mod"
+)
+
+// BaseURL is the Porkbun API root. It is a var so tests can point it at an
+// httptest.Server.
+var BaseURL = "https://porkbun.com/api/json/v3"
+
+type apiResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	Domains []struct {
+		Domain string `json:"domain"`
+	} `json:"domains,omitempty"`
+	Records []record `json:"records,omitempty"`
+}
+
+type record struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	TTL     string `json:"ttl"`
+	Prio    string `json:"prio,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+// Provider talks to the Porkbun API on behalf of a single account.
+type Provider struct {
+	apiKey    string
+	secretKey string
+	client    *rateLimitedClient
+}
+
+// New returns a Provider authenticated with the given Porkbun API key pair.
+func New(apiKey, secretKey string) *Provider {
+	return &Provider{
+		apiKey:    apiKey,
+		secretKey: secretKey,
+		client:    newRateLimitedClient(),
+	}
+}
+
+// transientErrorSubstrings are Porkbun API error messages - returned with
+// an HTTP 200 and never seen by rateLimitedClient's status-code retry -
+// that mean the request was rejected as part of a burst rather than
+// rejected outright, and are safe to retry.
+var transientErrorSubstrings = []string{
+	"unable to create the dns record",
+}
+
+func isTransientError(message string) bool {
+	message = strings.ToLower(message)
+	for _, s := range transientErrorSubstrings {
+		if strings.Contains(message, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Provider) post(ctx context.Context, path string, extra map[string]interface{}) (*apiResponse, error) {
+	body := map[string]interface{}{
+		"apikey":    p.apiKey,
+		"secretkey": p.secretKey,
+	}
+	for k, v := range extra {
+		body[k] = v
+	}
+	requestBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	bo := backoff.New(minRequestInterval, 30*time.Second)
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, BaseURL+path, bytes.NewBuffer(requestBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		respBody, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		var response apiResponse
+		if err := json.Unmarshal(respBody, &response); err != nil {
+			return nil, err
+		}
+		if response.Status == "SUCCESS" {
+			return &response, nil
+		}
+		if attempt >= maxRetries || !isTransientError(response.Message) {
+			return nil, fmt.Errorf("API error: %s", response.Message)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(bo.Next()):
+		}
+	}
+}
+
+// ListDomains returns every domain in the account.
+func (p *Provider) ListDomains(ctx context.Context) ([]string, error) {
+	response, err := p.post(ctx, "/domain/listAll", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	domains := []string{}
+	for _, domain := range response.Domains {
+		domains = append(domains, domain.Domain)
+	}
+	return domains, nil
+}
+
+// ListRecords returns every DNS record for domain.
+func (p *Provider) ListRecords(ctx context.Context, domain string) ([]mod.Record, error) {
+	response, err := p.post(ctx, fmt.Sprintf("/dns/retrieve/%s", domain), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []mod.Record{}
+	for _, r := range response.Records {
+		records = append(records, fromPorkbunRecord(r, domain))
+	}
+	return records, nil
+}
+
+// CreateRecord adds a new DNS record to domain.
+func (p *Provider) CreateRecord(ctx context.Context, domain string, r mod.Record) error {
+	body := map[string]interface{}{
+		"name":    toPorkbunName(r.Name),
+		"type":    r.Type,
+		"content": r.Content,
+		"ttl":     r.TTL,
+	}
+	if r.Prio != nil {
+		body["prio"] = *r.Prio
+	}
+	if r.Notes != "" {
+		body["notes"] = r.Notes
+	}
+
+	_, err := p.post(ctx, fmt.Sprintf("/dns/create/%s", domain), body)
+	return err
+}
+
+// UpdateRecord edits the record with the given id in place, using
+// Porkbun's edit endpoint so unrelated records are left untouched.
+func (p *Provider) UpdateRecord(ctx context.Context, domain, id string, r mod.Record) error {
+	body := map[string]interface{}{
+		"name":    toPorkbunName(r.Name),
+		"type":    r.Type,
+		"content": r.Content,
+		"ttl":     r.TTL,
+	}
+	if r.Prio != nil {
+		body["prio"] = *r.Prio
+	}
+	if r.Notes != "" {
+		body["notes"] = r.Notes
+	}
+
+	_, err := p.post(ctx, fmt.Sprintf("/dns/edit/%s/%s", domain, id), body)
+	return err
+}
+
+// DeleteRecord removes the record with the given id from domain.
+func (p *Provider) DeleteRecord(ctx context.Context, domain, id string) error {
+	_, err := p.post(ctx, fmt.Sprintf("/dns/delete/%s/%s", domain, id), nil)
+	return err
+}
+
+func fromPorkbunRecord(r record, domain string) mod.Record {
+	out := mod.Record{
+		ID:      r.ID,
+		Name:    fromPorkbunName(r.Name, domain),
+		Type:    r.Type,
+		Content: r.Content,
+		Notes:   r.Notes,
+	}
+	fmt.Sscanf(r.TTL, "%d", &out.TTL)
+	if r.Prio != "" {
+		var prio int
+		fmt.Sscanf(r.Prio, "%d", &prio)
+		out.Prio = &prio
+	}
+	return out
+}
+
+// toPorkbunName converts our canonical record name - the bare subdomain,
+// or "@" for the domain apex - into the form Porkbun's create/edit
+// endpoints expect: "" for the apex, since they treat a literal "@" as
+// the host "@.<domain>" rather than the root.
+func toPorkbunName(name string) string {
+	if name == "@" {
+		return ""
+	}
+	return name
+}
+
+// fromPorkbunName converts the name Porkbun's retrieve endpoint returns -
+// the bare subdomain, the FQDN, or "" for the apex - into our canonical
+// form, where the apex is always "@".
+func fromPorkbunName(name, domain string) string {
+	name = strings.TrimSuffix(name, "."+domain)
+	if name == "" || name == domain {
+		return "@"
+	}
+	return name
+}
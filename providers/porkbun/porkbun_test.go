@@ -0,0 +1,190 @@
+package porkbun
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mikerybka/update-domains/mod"
+)
+
+func withTestServer(t *testing.T, handler http.HandlerFunc) *Provider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	orig := BaseURL
+	BaseURL = server.URL
+	t.Cleanup(func() { BaseURL = orig })
+
+	return New("key", "secret")
+}
+
+func TestListDomains(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		status  int
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "success",
+			body:   `{"status":"SUCCESS","domains":[{"domain":"example.com"},{"domain":"example.org"}]}`,
+			status: http.StatusOK,
+			want:   []string{"example.com", "example.org"},
+		},
+		{
+			name:    "api error",
+			body:    `{"status":"ERROR","message":"invalid key"}`,
+			status:  http.StatusOK,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/domain/listAll" {
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			got, err := p.ListDomains(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListDomains() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ListDomains() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("ListDomains() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateAndDeleteRecord(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		body    string
+		wantErr bool
+	}{
+		{name: "create success", path: "/dns/create/example.com", body: `{"status":"SUCCESS"}`},
+		{name: "create error", path: "/dns/create/example.com", body: `{"status":"ERROR","message":"invalid record type"}`, wantErr: true},
+		{name: "delete success", path: "/dns/delete/example.com/1", body: `{"status":"SUCCESS"}`},
+		{name: "delete error", path: "/dns/delete/example.com/1", body: `{"status":"ERROR","message":"record not found"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.path {
+					t.Errorf("unexpected path: %s, want %s", r.URL.Path, tt.path)
+				}
+				w.Write([]byte(tt.body))
+			})
+
+			var err error
+			if tt.path == "/dns/create/example.com" {
+				err = p.CreateRecord(context.Background(), "example.com", mod.Record{
+					Name: "@", Type: "A", Content: "1.2.3.4", TTL: 300,
+				})
+			} else {
+				err = p.DeleteRecord(context.Background(), "example.com", "1")
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApexNameRoundTrip(t *testing.T) {
+	var sentName string
+	p := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dns/create/example.com":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			sentName, _ = body["name"].(string)
+			w.Write([]byte(`{"status":"SUCCESS"}`))
+		case "/dns/retrieve/example.com":
+			w.Write([]byte(`{"status":"SUCCESS","records":[` +
+				`{"id":"1","name":"example.com","type":"A","content":"1.2.3.4","ttl":"300"},` +
+				`{"id":"2","name":"www.example.com","type":"A","content":"1.2.3.4","ttl":"300"}` +
+				`]}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	})
+
+	if err := p.CreateRecord(context.Background(), "example.com", mod.Record{
+		Name: "@", Type: "A", Content: "1.2.3.4", TTL: 300,
+	}); err != nil {
+		t.Fatalf("CreateRecord() error = %v", err)
+	}
+	if sentName != "" {
+		t.Errorf(`CreateRecord() sent name %q, want "" (Porkbun's apex form)`, sentName)
+	}
+
+	records, err := p.ListRecords(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("ListRecords() error = %v", err)
+	}
+	want := map[string]string{"1": "@", "2": "www"}
+	for _, r := range records {
+		if r.Name != want[r.ID] {
+			t.Errorf("record %s Name = %q, want %q", r.ID, r.Name, want[r.ID])
+		}
+	}
+}
+
+func TestPostRetriesOnTransientAPIError(t *testing.T) {
+	var requests int
+	p := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.Write([]byte(`{"status":"ERROR","message":"Unable to create the DNS record."}`))
+			return
+		}
+		w.Write([]byte(`{"status":"SUCCESS"}`))
+	})
+
+	if err := p.CreateRecord(context.Background(), "example.com", mod.Record{
+		Name: "www", Type: "A", Content: "1.2.3.4", TTL: 300,
+	}); err != nil {
+		t.Fatalf("CreateRecord() error = %v", err)
+	}
+	if requests != 3 {
+		t.Fatalf("requests = %d, want 3", requests)
+	}
+}
+
+func TestPostDoesNotRetryOnTerminalAPIError(t *testing.T) {
+	var requests int
+	p := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"status":"ERROR","message":"invalid record type"}`))
+	})
+
+	if err := p.CreateRecord(context.Background(), "example.com", mod.Record{
+		Name: "www", Type: "A", Content: "1.2.3.4", TTL: 300,
+	}); err == nil {
+		t.Fatal("CreateRecord() error = nil, want invalid record type error")
+	}
+	if requests != 1 {
+		t.Fatalf("requests = %d, want 1 (no retry on a terminal error)", requests)
+	}
+}
@@ -0,0 +1,46 @@
+// Package providers defines the Provider interface implemented by each
+// supported DNS registrar, plus a registry for constructing one by name.
+package providers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mikerybka/update-domains/mod"
+	"github.com/mikerybka/update-domains/providers/cloudflare"
+	"github.com/mikerybka/update-domains/providers/gandi"
+	"github.com/mikerybka/update-domains/providers/namecheap"
+	"github.com/mikerybka/update-domains/providers/porkbun"
+	"github.com/mikerybka/update-domains/providers/route53"
+)
+
+// Provider is implemented by each registrar backend so the rest of the
+// program can manage DNS records without knowing which registrar is behind
+// a given domain.
+type Provider interface {
+	ListDomains(ctx context.Context) ([]string, error)
+	ListRecords(ctx context.Context, domain string) ([]mod.Record, error)
+	CreateRecord(ctx context.Context, domain string, record mod.Record) error
+	UpdateRecord(ctx context.Context, domain string, id string, record mod.Record) error
+	DeleteRecord(ctx context.Context, domain string, id string) error
+}
+
+// New constructs the Provider registered under name, configured with the
+// given credentials. The accepted credential keys are provider-specific;
+// the switch below lists the keys each provider reads.
+func New(name string, credentials map[string]string) (Provider, error) {
+	switch name {
+	case "porkbun":
+		return porkbun.New(credentials["api_key"], credentials["secret_key"]), nil
+	case "namecheap":
+		return namecheap.New(credentials["api_user"], credentials["api_key"], credentials["username"], credentials["client_ip"]), nil
+	case "gandi":
+		return gandi.New(credentials["api_key"]), nil
+	case "cloudflare":
+		return cloudflare.New(credentials["api_token"]), nil
+	case "route53":
+		return route53.New(credentials["access_key_id"], credentials["secret_access_key"], credentials["region"]), nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", name)
+	}
+}
@@ -0,0 +1,263 @@
+// Package route53 implements the providers.Provider interface against the
+// AWS Route 53 REST API, signed with SigV4. It talks to the API directly
+// over HTTP rather than depending on the AWS SDK, matching the rest of this
+// module's stdlib-only approach.
+package route53
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/mikerybka/update-domains/mod"
+)
+
+// BaseURL is the Route 53 API root. It is a var so tests can point it at an
+// httptest.Server.
+var BaseURL = "https://route53.amazonaws.com/2013-04-01"
+
+type hostedZonesResponse struct {
+	HostedZones []struct {
+		ID   string `xml:"Id"`
+		Name string `xml:"Name"`
+	} `xml:"HostedZones>HostedZone"`
+}
+
+type resourceRecordSetsResponse struct {
+	ResourceRecordSets []recordSet `xml:"ResourceRecordSets>ResourceRecordSet"`
+}
+
+type recordSet struct {
+	Name            string `xml:"Name"`
+	Type            string `xml:"Type"`
+	TTL             int    `xml:"TTL"`
+	ResourceRecords []struct {
+		Value string `xml:"Value"`
+	} `xml:"ResourceRecords>ResourceRecord"`
+}
+
+type changeBatch struct {
+	XMLName xml.Name `xml:"ChangeResourceRecordSetsRequest"`
+	NS      string   `xml:"xmlns,attr"`
+	Changes []change `xml:"ChangeBatch>Changes>Change"`
+}
+
+type change struct {
+	Action            string    `xml:"Action"`
+	ResourceRecordSet recordSet `xml:"ResourceRecordSet"`
+}
+
+// Provider talks to the Route 53 API on behalf of a single AWS account.
+type Provider struct {
+	accessKeyID     string
+	secretAccessKey string
+	region          string
+	client          *http.Client
+}
+
+// New returns a Provider authenticated with the given AWS credentials.
+// region is used only for SigV4 signing; Route 53 itself is a global
+// service.
+func New(accessKeyID, secretAccessKey, region string) *Provider {
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &Provider{
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		region:          region,
+		client:          http.DefaultClient,
+	}
+}
+
+func (p *Provider) do(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > 0 {
+		req.Header.Set("Content-Type", "application/xml")
+	}
+
+	if err := sign(req, body, p.accessKeyID, p.secretAccessKey, p.region); err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("API error: %s: %s", resp.Status, string(respBody))
+	}
+	return respBody, nil
+}
+
+// ListDomains returns the name of every hosted zone in the account.
+func (p *Provider) ListDomains(ctx context.Context) ([]string, error) {
+	body, err := p.do(ctx, http.MethodGet, "/hostedzone", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response hostedZonesResponse
+	if err := xml.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+
+	domains := []string{}
+	for _, z := range response.HostedZones {
+		domains = append(domains, strings.TrimSuffix(z.Name, "."))
+	}
+	return domains, nil
+}
+
+// hostedZoneID looks up the hosted zone id for a domain name.
+func (p *Provider) hostedZoneID(ctx context.Context, domain string) (string, error) {
+	body, err := p.do(ctx, http.MethodGet, "/hostedzone", nil)
+	if err != nil {
+		return "", err
+	}
+
+	var response hostedZonesResponse
+	if err := xml.Unmarshal(body, &response); err != nil {
+		return "", err
+	}
+
+	want := domain + "."
+	for _, z := range response.HostedZones {
+		if z.Name == want {
+			return strings.TrimPrefix(z.ID, "/hostedzone/"), nil
+		}
+	}
+	return "", fmt.Errorf("no hosted zone found for domain %q", domain)
+}
+
+// ListRecords returns every resource record set for domain.
+func (p *Provider) ListRecords(ctx context.Context, domain string) ([]mod.Record, error) {
+	zoneID, err := p.hostedZoneID(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.do(ctx, http.MethodGet, fmt.Sprintf("/hostedzone/%s/rrset", zoneID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response resourceRecordSetsResponse
+	if err := xml.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+
+	records := []mod.Record{}
+	for _, rs := range response.ResourceRecordSets {
+		records = append(records, fromRecordSet(zoneID, rs))
+	}
+	return records, nil
+}
+
+// CreateRecord adds a new resource record set to domain.
+func (p *Provider) CreateRecord(ctx context.Context, domain string, r mod.Record) error {
+	zoneID, err := p.hostedZoneID(ctx, domain)
+	if err != nil {
+		return err
+	}
+	return p.change(ctx, zoneID, "UPSERT", toRecordSet(domain, r))
+}
+
+// UpdateRecord replaces the record with the given id. Route 53 has no
+// separate edit call: ChangeResourceRecordSets with action UPSERT both
+// creates and updates a record set, so this is identical to CreateRecord.
+func (p *Provider) UpdateRecord(ctx context.Context, domain, id string, r mod.Record) error {
+	return p.CreateRecord(ctx, domain, r)
+}
+
+// DeleteRecord removes the record with the given id (name|type) from
+// domain. Route 53 has no record id of its own, so ListRecords encodes
+// "name|type" as the id.
+func (p *Provider) DeleteRecord(ctx context.Context, domain, id string) error {
+	zoneID, err := p.hostedZoneID(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	records, err := p.ListRecords(ctx, domain)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		if r.ID == id {
+			return p.change(ctx, zoneID, "DELETE", toRecordSet(domain, r))
+		}
+	}
+	return fmt.Errorf("no record found with id %q", id)
+}
+
+func (p *Provider) change(ctx context.Context, zoneID, action string, rs recordSet) error {
+	batch := changeBatch{
+		NS: "https://route53.amazonaws.com/doc/2013-04-01/",
+		Changes: []change{
+			{Action: action, ResourceRecordSet: rs},
+		},
+	}
+	body, err := xml.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.do(ctx, http.MethodPost, fmt.Sprintf("/hostedzone/%s/rrset", zoneID), body)
+	return err
+}
+
+func fromRecordSet(zoneID string, rs recordSet) mod.Record {
+	content := ""
+	if len(rs.ResourceRecords) > 0 {
+		content = rs.ResourceRecords[0].Value
+	}
+	return mod.Record{
+		ID:      rs.Name + "|" + rs.Type,
+		Name:    strings.TrimSuffix(rs.Name, "."),
+		Type:    rs.Type,
+		Content: content,
+		TTL:     rs.TTL,
+	}
+}
+
+func toRecordSet(domain string, r mod.Record) recordSet {
+	rs := recordSet{
+		Name: qualifyName(r.Name, domain),
+		Type: r.Type,
+		TTL:  r.TTL,
+	}
+	rs.ResourceRecords = append(rs.ResourceRecords, struct {
+		Value string `xml:"Value"`
+	}{Value: r.Content})
+	return rs
+}
+
+// qualifyName converts name - our canonical "@" for the apex or a bare
+// subdomain, but also tolerates a name already fully qualified against
+// domain (as ListRecords returns and DeleteRecord then passes back in) -
+// into the fully-qualified name Route 53 requires: "example.com" for the
+// apex, "www.example.com" for a subdomain.
+func qualifyName(name, domain string) string {
+	name = strings.TrimSuffix(name, ".")
+	if name == "@" || name == "" || name == domain {
+		return domain
+	}
+	if strings.HasSuffix(name, "."+domain) {
+		return name
+	}
+	return name + "." + domain
+}
@@ -0,0 +1,121 @@
+package route53
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mikerybka/update-domains/mod"
+)
+
+func withTestServer(t *testing.T, handler http.HandlerFunc) *Provider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	orig := BaseURL
+	BaseURL = server.URL
+	t.Cleanup(func() { BaseURL = orig })
+
+	return New("key", "secret", "us-east-1")
+}
+
+const listZonesBody = `<ListHostedZonesResponse><HostedZones><HostedZone>` +
+	`<Id>/hostedzone/Z1</Id><Name>example.com.</Name></HostedZone>` +
+	`</HostedZones></ListHostedZonesResponse>`
+
+func TestListDomains(t *testing.T) {
+	p := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/hostedzone" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Write([]byte(listZonesBody))
+	})
+
+	got, err := p.ListDomains(context.Background())
+	if err != nil {
+		t.Fatalf("ListDomains() error = %v", err)
+	}
+	want := []string{"example.com"}
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Fatalf("ListDomains() = %v, want %v", got, want)
+	}
+}
+
+func TestApexNameIsQualified(t *testing.T) {
+	var sent change
+	p := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/hostedzone":
+			w.Write([]byte(listZonesBody))
+		case r.URL.Path == "/hostedzone/Z1/rrset":
+			var batch changeBatch
+			xml.NewDecoder(r.Body).Decode(&batch)
+			sent = batch.Changes[0]
+			w.Write([]byte(`<ChangeResourceRecordSetsResponse/>`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	})
+
+	if err := p.CreateRecord(context.Background(), "example.com", mod.Record{
+		Name: "@", Type: "A", Content: "1.2.3.4", TTL: 300,
+	}); err != nil {
+		t.Fatalf("CreateRecord() error = %v", err)
+	}
+	if sent.ResourceRecordSet.Name != "example.com" {
+		t.Errorf("CreateRecord() sent Name = %q, want %q", sent.ResourceRecordSet.Name, "example.com")
+	}
+
+	if err := p.CreateRecord(context.Background(), "example.com", mod.Record{
+		Name: "www", Type: "A", Content: "1.2.3.4", TTL: 300,
+	}); err != nil {
+		t.Fatalf("CreateRecord() error = %v", err)
+	}
+	if sent.ResourceRecordSet.Name != "www.example.com" {
+		t.Errorf("CreateRecord() sent Name = %q, want %q", sent.ResourceRecordSet.Name, "www.example.com")
+	}
+}
+
+func TestListRecordsStripsTrailingDot(t *testing.T) {
+	p := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/hostedzone":
+			w.Write([]byte(listZonesBody))
+		case "/hostedzone/Z1/rrset":
+			w.Write([]byte(`<ListResourceRecordSetsResponse><ResourceRecordSets><ResourceRecordSet>` +
+				`<Name>example.com.</Name><Type>A</Type><TTL>300</TTL>` +
+				`<ResourceRecords><ResourceRecord><Value>1.2.3.4</Value></ResourceRecord></ResourceRecords>` +
+				`</ResourceRecordSet></ResourceRecordSets></ListResourceRecordSetsResponse>`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	})
+
+	records, err := p.ListRecords(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("ListRecords() error = %v", err)
+	}
+	if len(records) != 1 || records[0].Name != "example.com" {
+		t.Fatalf("ListRecords() = %v, want a single record named %q", records, "example.com")
+	}
+}
+
+func TestDeleteRecordNotFound(t *testing.T) {
+	p := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/hostedzone":
+			w.Write([]byte(listZonesBody))
+		case "/hostedzone/Z1/rrset":
+			w.Write([]byte(`<ListResourceRecordSetsResponse><ResourceRecordSets/></ListResourceRecordSetsResponse>`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	})
+
+	if err := p.DeleteRecord(context.Background(), "example.com", "missing|A"); err == nil {
+		t.Fatal("DeleteRecord() error = nil, want error for missing record")
+	}
+}
@@ -0,0 +1,187 @@
+// Package reconcile computes and applies the minimal set of changes needed
+// to bring a domain's DNS records in line with a desired state, instead of
+// deleting and recreating everything on every run.
+package reconcile
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mikerybka/update-domains/config"
+	"github.com/mikerybka/update-domains/mod"
+	"github.com/mikerybka/update-domains/providers"
+)
+
+// Action reports a single create/update/delete taken (or, in dry-run mode,
+// planned) against a domain.
+type Action struct {
+	Domain   string `json:"domain"`
+	Action   string `json:"action"`
+	RecordID string `json:"record_id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Reporter is notified of each Action as Apply performs or plans it.
+type Reporter func(Action)
+
+// Options controls how Apply carries out a Diff.
+type Options struct {
+	// DryRun, when true, reports every action that would be taken without
+	// calling the provider.
+	DryRun bool
+	// Report, if non-nil, is called once per action.
+	Report Reporter
+}
+
+// UpdateDomain reconciles domain's records, through provider, against the
+// set declared in domainCfg - substituting ip for any dynamic record
+// content - preserving unrelated records unless prune is set.
+func UpdateDomain(ctx context.Context, provider providers.Provider, domain, ip string, domainCfg config.DomainConfig, prune bool, opts Options) error {
+	existing, err := provider.ListRecords(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	recordCfgs := domainCfg.DesiredRecords()
+	desired := make([]mod.Record, len(recordCfgs))
+	for i, rc := range recordCfgs {
+		desired[i] = rc.Resolve(ip)
+	}
+
+	diff := Plan(existing, desired, domain, prune)
+	return Apply(ctx, provider, domain, diff, opts)
+}
+
+// Update is an edit to an existing record, identified by its provider id.
+type Update struct {
+	ID     string
+	Record mod.Record
+}
+
+// Diff is the set of changes needed to turn an existing record set into a
+// desired one.
+type Diff struct {
+	Creates []mod.Record
+	Updates []Update
+	Deletes []mod.Record
+}
+
+// Plan compares existing against desired and returns the minimal diff.
+// Records are matched by Type and by Name once both sides are reduced to
+// a canonical form relative to domain - some providers report the apex as
+// the bare domain or a trailing-dot FQDN rather than "@". A desired record
+// matching an existing one with different Content or TTL becomes an
+// Update; a desired record with no match becomes a Create. Existing
+// records with no match in desired only become Deletes when prune is true
+// - otherwise they're left alone, so unrelated records (MX, TXT, NS,
+// CAA, ...) survive by default.
+func Plan(existing, desired []mod.Record, domain string, prune bool) Diff {
+	unmatched := make([]mod.Record, len(existing))
+	copy(unmatched, existing)
+
+	var diff Diff
+	for _, want := range desired {
+		wantName := canonicalName(want.Name, domain)
+		idx := -1
+		for i, got := range unmatched {
+			if canonicalName(got.Name, domain) == wantName && got.Type == want.Type {
+				idx = i
+				break
+			}
+		}
+
+		if idx == -1 {
+			diff.Creates = append(diff.Creates, want)
+			continue
+		}
+
+		got := unmatched[idx]
+		unmatched = append(unmatched[:idx], unmatched[idx+1:]...)
+
+		if got.Content != want.Content || got.TTL != want.TTL {
+			diff.Updates = append(diff.Updates, Update{ID: got.ID, Record: want})
+		}
+	}
+
+	if prune {
+		diff.Deletes = unmatched
+	}
+
+	return diff
+}
+
+// canonicalName reduces name to the bare form used to compare records
+// across providers: lower-cased, with any trailing dot and "."+domain
+// suffix stripped, and the apex - whether reported as "@", "", the bare
+// domain, or the FQDN - normalized to "@".
+func canonicalName(name, domain string) string {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	domain = strings.ToLower(domain)
+	name = strings.TrimSuffix(name, "."+domain)
+	if name == "" || name == domain {
+		return "@"
+	}
+	return name
+}
+
+// Apply executes diff against provider for the given domain, or, if
+// opts.DryRun is set, only reports what it would have done.
+func Apply(ctx context.Context, provider providers.Provider, domain string, diff Diff, opts Options) error {
+	report := opts.Report
+	if report == nil {
+		report = func(Action) {}
+	}
+
+	for _, r := range diff.Creates {
+		action := Action{Domain: domain, Action: "create", Name: r.Name, Type: r.Type}
+		if opts.DryRun {
+			action.Status = "planned"
+			report(action)
+			continue
+		}
+		if err := provider.CreateRecord(ctx, domain, r); err != nil {
+			action.Status, action.Error = "error", err.Error()
+			report(action)
+			return err
+		}
+		action.Status = "ok"
+		report(action)
+	}
+
+	for _, u := range diff.Updates {
+		action := Action{Domain: domain, Action: "update", RecordID: u.ID, Name: u.Record.Name, Type: u.Record.Type}
+		if opts.DryRun {
+			action.Status = "planned"
+			report(action)
+			continue
+		}
+		if err := provider.UpdateRecord(ctx, domain, u.ID, u.Record); err != nil {
+			action.Status, action.Error = "error", err.Error()
+			report(action)
+			return err
+		}
+		action.Status = "ok"
+		report(action)
+	}
+
+	for _, r := range diff.Deletes {
+		action := Action{Domain: domain, Action: "delete", RecordID: r.ID, Name: r.Name, Type: r.Type}
+		if opts.DryRun {
+			action.Status = "planned"
+			report(action)
+			continue
+		}
+		if err := provider.DeleteRecord(ctx, domain, r.ID); err != nil {
+			action.Status, action.Error = "error", err.Error()
+			report(action)
+			return err
+		}
+		action.Status = "ok"
+		report(action)
+	}
+
+	return nil
+}
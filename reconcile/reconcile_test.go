@@ -0,0 +1,86 @@
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/mikerybka/update-domains/mod"
+)
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name        string
+		existing    []mod.Record
+		desired     []mod.Record
+		prune       bool
+		wantCreates int
+		wantUpdates int
+		wantDeletes int
+	}{
+		{
+			name:        "empty zone gets created",
+			existing:    nil,
+			desired:     []mod.Record{{Name: "@", Type: "A", Content: "1.2.3.4", TTL: 300}},
+			wantCreates: 1,
+		},
+		{
+			name: "matching record is left alone",
+			existing: []mod.Record{
+				{ID: "1", Name: "@", Type: "A", Content: "1.2.3.4", TTL: 300},
+			},
+			desired: []mod.Record{{Name: "@", Type: "A", Content: "1.2.3.4", TTL: 300}},
+		},
+		{
+			name: "changed content is an update, not a delete+create",
+			existing: []mod.Record{
+				{ID: "1", Name: "@", Type: "A", Content: "1.2.3.4", TTL: 300},
+			},
+			desired:     []mod.Record{{Name: "@", Type: "A", Content: "5.6.7.8", TTL: 300}},
+			wantUpdates: 1,
+		},
+		{
+			name: "unrelated records survive by default",
+			existing: []mod.Record{
+				{ID: "1", Name: "@", Type: "A", Content: "1.2.3.4", TTL: 300},
+				{ID: "2", Name: "@", Type: "MX", Content: "mail.example.com", TTL: 300},
+				{ID: "3", Name: "@", Type: "TXT", Content: "v=spf1 -all", TTL: 300},
+			},
+			desired: []mod.Record{{Name: "@", Type: "A", Content: "1.2.3.4", TTL: 300}},
+		},
+		{
+			name: "unrelated records are pruned when requested",
+			existing: []mod.Record{
+				{ID: "1", Name: "@", Type: "A", Content: "1.2.3.4", TTL: 300},
+				{ID: "2", Name: "@", Type: "MX", Content: "mail.example.com", TTL: 300},
+			},
+			desired:     []mod.Record{{Name: "@", Type: "A", Content: "1.2.3.4", TTL: 300}},
+			prune:       true,
+			wantDeletes: 1,
+		},
+		{
+			name: "existing record reported as an FQDN still matches the bare desired name",
+			existing: []mod.Record{
+				{ID: "1", Name: "example.com", Type: "A", Content: "1.2.3.4", TTL: 300},
+				{ID: "2", Name: "www.example.com.", Type: "A", Content: "1.2.3.4", TTL: 300},
+			},
+			desired: []mod.Record{
+				{Name: "@", Type: "A", Content: "1.2.3.4", TTL: 300},
+				{Name: "www", Type: "A", Content: "1.2.3.4", TTL: 300},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := Plan(tt.existing, tt.desired, "example.com", tt.prune)
+			if len(diff.Creates) != tt.wantCreates {
+				t.Errorf("Creates = %d, want %d", len(diff.Creates), tt.wantCreates)
+			}
+			if len(diff.Updates) != tt.wantUpdates {
+				t.Errorf("Updates = %d, want %d", len(diff.Updates), tt.wantUpdates)
+			}
+			if len(diff.Deletes) != tt.wantDeletes {
+				t.Errorf("Deletes = %d, want %d", len(diff.Deletes), tt.wantDeletes)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Package state persists the daemon's last-known public IP and the time it
+// was last applied, so a restart doesn't cause a spurious update.
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// State is the daemon's persisted view of the world.
+type State struct {
+	LastIP         string    `json:"last_ip"`
+	LastUpdateTime time.Time `json:"last_update_time"`
+}
+
+// Load reads the state file at path. A missing file is not an error - it
+// means the daemon has never successfully updated, so a zero-value State
+// is returned.
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Save writes s to the state file at path.
+func Save(path string, s *State) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,34 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	s, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if s.LastIP != "" {
+		t.Fatalf("Load() of missing file = %+v, want zero value", s)
+	}
+}
+
+func TestSaveAndLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	want := &State{LastIP: "1.2.3.4", LastUpdateTime: time.Now().UTC().Truncate(time.Second)}
+
+	if err := Save(path, want); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got.LastIP != want.LastIP || !got.LastUpdateTime.Equal(want.LastUpdateTime) {
+		t.Fatalf("Load() = %+v, want %+v", got, want)
+	}
+}